@@ -0,0 +1,296 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cosign
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	in_toto "github.com/in-toto/in-toto-golang/in_toto"
+	"github.com/pkg/errors"
+
+	sigs "github.com/sigstore/cosign/pkg/signature"
+	k8smnfutil "github.com/sigstore/k8s-manifest-sigstore/pkg/util"
+)
+
+// dsseEnvelope is a DSSE envelope (https://github.com/secure-systems-lab/dsse) carrying
+// a single in-toto statement, extended with an optional base64-encoded Fulcio cert
+// alongside the signature so the envelope stays self-contained the way the existing
+// `messageSignature`/`certificate` annotation pair already works for plain signatures.
+type dsseEnvelope struct {
+	PayloadType string          `json:"payloadType"`
+	Payload     string          `json:"payload"`
+	Signatures  []dsseSignature `json:"signatures"`
+}
+
+type dsseSignature struct {
+	Sig  string `json:"sig"`
+	Cert string `json:"cert,omitempty"`
+	// Timestamp is a base64-encoded RFC3161 timestamp token covering Sig, the same
+	// offline timestamping SignManifest adds for plain message signatures.
+	Timestamp string `json:"timestamp,omitempty"`
+}
+
+// dssePAE computes the DSSE Pre-Authentication Encoding for (payloadType, payload),
+// which is what gets signed rather than the raw payload.
+func dssePAE(payloadType, payload string) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// PredicatePolicyType selects the language VerifyAttestationOpts.PredicatePolicy is
+// written in.
+type PredicatePolicyType string
+
+const (
+	// PredicatePolicyTypeCEL evaluates PredicatePolicy as a CEL expression.
+	PredicatePolicyTypeCEL PredicatePolicyType = "cel"
+	// PredicatePolicyTypeRego evaluates PredicatePolicy as a Rego policy.
+	PredicatePolicyTypeRego PredicatePolicyType = "rego"
+)
+
+// VerifyAttestationOpts customizes VerifyManifestAttestation.
+type VerifyAttestationOpts struct {
+	// PubkeyPath verifies the DSSE envelope with a public key instead of a Fulcio cert
+	// + Rekor entry. Leave empty for keyless verification.
+	PubkeyPath string
+
+	// InsecureSkipSCTVerify skips SCT verification on the Fulcio cert covering the
+	// envelope signature, same as VerifyBlobOptions.InsecureSkipSCTVerify. The envelope
+	// carries no issuer certificate, which SCT verification requires, so an envelope
+	// signed with a Fulcio cert verifies only when this is set.
+	InsecureSkipSCTVerify bool
+
+	// TSACertPoolPath verifies the envelope signature's RFC3161 timestamp, if
+	// SignAttestation recorded one, against the TSA certificate chain at this path. Leave
+	// empty to skip timestamp verification even when the envelope carries one.
+	TSACertPoolPath string
+
+	// PredicatePolicy, when set, is evaluated against the attestation's predicate after
+	// the signature and subject digest are verified. PredicatePolicyType selects
+	// whether it is a CEL expression or a Rego policy.
+	PredicatePolicy     string
+	PredicatePolicyType PredicatePolicyType
+}
+
+// AttestationResult describes how an in-toto attestation verified, mirroring
+// VerificationResult for the signature itself plus attestation-specific fields.
+type AttestationResult struct {
+	*VerificationResult
+
+	// PredicateType is the in-toto predicateType the attestation carries, e.g.
+	// "https://slsa.dev/provenance/v1", an SPDX or CycloneDX SBOM type, or a custom one.
+	PredicateType string
+
+	// PolicyEvaluated is true when opts.PredicatePolicy was set and evaluated.
+	PolicyEvaluated bool
+	// PolicyPassed is only meaningful when PolicyEvaluated is true.
+	PolicyPassed bool
+}
+
+// VerifyManifestAttestation verifies a DSSE-wrapped in-toto attestation covering a
+// Kubernetes manifest: the envelope signature (Fulcio cert + Rekor, Fulcio cert + TSA,
+// or a public key, the same trust paths as VerifyBlob), that the attestation's subject
+// digest matches the canonicalized manifest in msgBytes, and, if opts.PredicatePolicy is
+// set, a user-supplied policy over the predicate. This lets admission controllers reject
+// deploys whose provenance/SBOM metadata doesn't match policy.
+func VerifyManifestAttestation(msgBytes, attestationBytes []byte, opts VerifyAttestationOpts) (*AttestationResult, error) {
+	envelope := &dsseEnvelope{}
+	if err := json.Unmarshal(attestationBytes, envelope); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal DSSE envelope")
+	}
+	if envelope.PayloadType != in_toto.PayloadType {
+		return nil, errors.Errorf("unsupported DSSE payload type `%s`", envelope.PayloadType)
+	}
+
+	verificationResult, err := verifyDSSEEnvelope(envelope, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify attestation signature")
+	}
+
+	statementBytes, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode DSSE payload")
+	}
+	statement := &in_toto.Statement{}
+	if err := json.Unmarshal(statementBytes, statement); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal in-toto statement")
+	}
+
+	if err := verifySubjectDigest(statement, msgBytes); err != nil {
+		return nil, errors.Wrap(err, "attestation subject does not match manifest")
+	}
+
+	result := &AttestationResult{
+		VerificationResult: verificationResult,
+		PredicateType:      statement.PredicateType,
+	}
+
+	if opts.PredicatePolicy != "" {
+		result.PolicyEvaluated = true
+		passed, err := evaluatePredicatePolicy(statement.Predicate, opts.PredicatePolicy, opts.PredicatePolicyType)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to evaluate predicate policy")
+		}
+		result.PolicyPassed = passed
+		if !passed {
+			return result, errors.New("attestation predicate failed policy evaluation")
+		}
+	}
+
+	return result, nil
+}
+
+// verifyDSSEEnvelope verifies the envelope's signature using the same Fulcio/Rekor/TSA
+// or public-key trust paths VerifyBlob supports, treating the PAE-encoded payload as the
+// signed message.
+func verifyDSSEEnvelope(envelope *dsseEnvelope, opts VerifyAttestationOpts) (*VerificationResult, error) {
+	if len(envelope.Signatures) == 0 {
+		return nil, errors.New("DSSE envelope has no signatures")
+	}
+
+	pae := dssePAE(envelope.PayloadType, envelope.Payload)
+	sig := envelope.Signatures[0]
+
+	sigBytes, err := base64.StdEncoding.DecodeString(sig.Sig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode signature")
+	}
+
+	var certBytes []byte
+	if sig.Cert != "" {
+		certBytes, err = base64.StdEncoding.DecodeString(sig.Cert)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode certificate")
+		}
+	}
+
+	var tsaBytes []byte
+	tsaCertPoolPath := opts.TSACertPoolPath
+	if sig.Timestamp != "" && tsaCertPoolPath != "" {
+		tsaBytes, err = base64.StdEncoding.DecodeString(sig.Timestamp)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode timestamp token")
+		}
+	}
+
+	var pubkeyPath *string
+	if opts.PubkeyPath != "" {
+		pubkeyPath = &opts.PubkeyPath
+		if _, err := sigs.PublicKeyFromKeyRef(context.Background(), opts.PubkeyPath); err != nil {
+			return nil, errors.Wrap(err, "failed to load public key")
+		}
+	}
+
+	return VerifyBlob(
+		gzipAndBase64Encode(pae),
+		[]byte(base64.StdEncoding.EncodeToString(sigBytes)),
+		gzipAndBase64Encode(certBytes),
+		nil,
+		nil,
+		gzipAndBase64Encode(tsaBytes),
+		nil,
+		tsaCertPoolPath,
+		pubkeyPath,
+		VerifyBlobOptions{InsecureSkipSCTVerify: opts.InsecureSkipSCTVerify},
+	)
+}
+
+// gzipAndBase64Encode mirrors the gzip+base64 encoding VerifyBlob expects its message,
+// certificate and timestamp arguments in, the inverse of k8smnfutil.GzipDecompress.
+// VerifyBlob's signature argument is base64 only.
+func gzipAndBase64Encode(raw []byte) []byte {
+	if raw == nil {
+		return nil
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, _ = gz.Write(raw)
+	_ = gz.Close()
+	return []byte(base64.StdEncoding.EncodeToString(buf.Bytes()))
+}
+
+// verifySubjectDigest checks that at least one subject in the statement's subject list
+// matches the sha256 digest of the canonicalized manifest.
+func verifySubjectDigest(statement *in_toto.Statement, msgBytes []byte) error {
+	gzipMsg, _ := base64.StdEncoding.DecodeString(string(msgBytes))
+	rawMsg := k8smnfutil.GzipDecompress(gzipMsg)
+	sum := sha256.Sum256(rawMsg)
+	digest := hex.EncodeToString(sum[:])
+
+	for _, subject := range statement.Subject {
+		if subject.Digest["sha256"] == digest {
+			return nil
+		}
+	}
+	return errors.Errorf("no subject in attestation matches manifest digest sha256:%s", digest)
+}
+
+// evaluatePredicatePolicy evaluates policy (CEL or Rego, depending on policyType)
+// against predicate, which is the decoded in-toto predicate (SLSA provenance, SPDX,
+// CycloneDX, or a custom type).
+func evaluatePredicatePolicy(predicate interface{}, policy string, policyType PredicatePolicyType) (bool, error) {
+	switch policyType {
+	case PredicatePolicyTypeRego:
+		return evaluateRegoPolicy(predicate, policy)
+	case PredicatePolicyTypeCEL, "":
+		return evaluateCELPolicy(predicate, policy)
+	default:
+		return false, errors.Errorf("unsupported predicate policy type `%s`", policyType)
+	}
+}
+
+// SignAttestation wraps an in-toto statement (statementBytes, typically built by the
+// caller from a predicate plus a subject covering the manifest digest) in a DSSE
+// envelope and signs it, analogous to SignManifest for plain message signatures. The
+// returned bytes are gzip+base64 encoded, ready to store in the manifest's
+// `attestation` annotation.
+func SignAttestation(statementBytes []byte, keyPath, tsaServerURL string) ([]byte, error) {
+	payload := base64.StdEncoding.EncodeToString(statementBytes)
+	pae := dssePAE(in_toto.PayloadType, payload)
+
+	sigBytes, certBytes, tsaBytes, err := SignManifest(pae, keyPath, tsaServerURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign in-toto statement")
+	}
+
+	sig := dsseSignature{Sig: base64.StdEncoding.EncodeToString(sigBytes)}
+	if len(certBytes) > 0 {
+		sig.Cert = base64.StdEncoding.EncodeToString(certBytes)
+	}
+	if len(tsaBytes) > 0 {
+		sig.Timestamp = base64.StdEncoding.EncodeToString(tsaBytes)
+	}
+
+	envelope := &dsseEnvelope{
+		PayloadType: in_toto.PayloadType,
+		Payload:     payload,
+		Signatures:  []dsseSignature{sig},
+	}
+
+	envelopeJSON, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal DSSE envelope")
+	}
+
+	return gzipAndBase64Encode(envelopeJSON), nil
+}
@@ -0,0 +1,66 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cosign
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	in_toto "github.com/in-toto/in-toto-golang/in_toto"
+)
+
+func TestDssePAE(t *testing.T) {
+	got := string(dssePAE("application/vnd.in-toto+json", "payload"))
+	want := "DSSEv1 28 application/vnd.in-toto+json 7 payload"
+	if got != want {
+		t.Fatalf("dssePAE() = %q, want %q", got, want)
+	}
+}
+
+func manifestDigestHex(manifest []byte) string {
+	sum := sha256.Sum256(manifest)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestVerifySubjectDigest(t *testing.T) {
+	manifest := []byte("apiVersion: v1\nkind: ConfigMap\n")
+	msgBytes := gzipAndBase64Encode(manifest)
+
+	matching := &in_toto.Statement{
+		StatementHeader: in_toto.StatementHeader{
+			Subject: []in_toto.Subject{
+				{Digest: map[string]string{"sha256": manifestDigestHex(manifest)}},
+			},
+		},
+	}
+	if err := verifySubjectDigest(matching, msgBytes); err != nil {
+		t.Fatalf("expected matching digest to verify, got %s", err)
+	}
+
+	mismatching := &in_toto.Statement{
+		StatementHeader: in_toto.StatementHeader{
+			Subject: []in_toto.Subject{
+				{Digest: map[string]string{"sha256": strings.Repeat("0", 64)}},
+			},
+		},
+	}
+	if err := verifySubjectDigest(mismatching, msgBytes); err == nil {
+		t.Fatal("expected a mismatching digest to fail verification")
+	}
+}
@@ -0,0 +1,93 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cosign
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+
+	protobundle "github.com/sigstore/protobuf-specs/gen/pb-go/bundle/v1"
+	"github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore-go/pkg/root"
+	sgverify "github.com/sigstore/sigstore-go/pkg/verify"
+
+	k8ssigx509 "github.com/sigstore/k8s-manifest-sigstore/pkg/util/sigtypes/x509"
+)
+
+// VerifyBundleFile verifies a Sigstore bundle (protobuf, `sigstore-bundle` v0.3) in a
+// single offline pass: it checks the DSSE/message signature, the embedded verification
+// material (cert chain or public key hint), the inclusion proof/SET, and an optional
+// RFC3161 timestamp, all against trustedRoot. On success it returns the signer identity
+// and the verified signing time (Rekor integrated time or TSA time, whichever the bundle
+// carries).
+func VerifyBundleFile(msgBytes, bundleJSON []byte, trustedRoot *root.TrustedRoot) (*VerificationResult, error) {
+	b := &bundle.Bundle{Bundle: &protobundle.Bundle{}}
+	if err := b.UnmarshalJSON(bundleJSON); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal sigstore bundle")
+	}
+
+	verifier, err := sgverify.NewVerifier(trustedRoot, sgverify.WithSignedCertificateTimestamps(1), sgverify.WithTransparencyLog(1), sgverify.WithObserverTimestamps(1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create sigstore-go verifier")
+	}
+
+	sgResult, err := verifier.Verify(b, sgverify.NewPolicy(sgverify.WithArtifact(bytes.NewReader(msgBytes))))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify sigstore bundle")
+	}
+
+	result := &VerificationResult{Verified: true, Mechanism: VerificationMechanismBundle, SCTStatus: SCTStatusNotApplicable}
+	if sgResult.Signature != nil && sgResult.Signature.Certificate != nil {
+		result.SignerName = k8ssigx509.GetNameInfoFromX509Cert(sgResult.Signature.Certificate)
+		result.SCTStatus = SCTStatusVerified
+	}
+
+	for _, vt := range sgResult.VerifiedTimestamps {
+		t := vt.Time.Unix()
+		result.TSASignedTime = &t
+		break
+	}
+
+	return result, nil
+}
+
+// SignBundle signs msgBytes and packages the signature, the Fulcio certificate (none
+// when keyPath is set) and the optional RFC3161 timestamp into a single Sigstore bundle
+// (protobuf, v0.3), so a manifest can ship with one self-contained `bundle` annotation
+// instead of separate `messageSignature`/`certificate`/`bundle` annotations. The bundle
+// carries no Rekor inclusion proof or SET, since SignManifest does not return the
+// transparency log entry, so VerifyBundleFile, which requires a transparency log
+// entry, does not accept it.
+func SignBundle(msgBytes []byte, keyPath, tsaServerURL string) ([]byte, error) {
+	sigBytes, certBytes, tsaBytes, err := SignManifest(msgBytes, keyPath, tsaServerURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign manifest")
+	}
+
+	b, err := bundle.NewBundleFromParts(certBytes, sigBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build sigstore bundle")
+	}
+	if len(tsaBytes) > 0 {
+		if err := b.AddTimestampVerificationData(tsaBytes); err != nil {
+			return nil, errors.Wrap(err, "failed to attach RFC3161 timestamp to bundle")
+		}
+	}
+
+	return b.MarshalJSON()
+}
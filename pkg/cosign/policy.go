@@ -0,0 +1,107 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cosign
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/cel-go/cel"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/pkg/errors"
+)
+
+// evaluateCELPolicy compiles and runs policy as a CEL expression against predicate
+// (exposed to the expression as the `predicate` variable) and returns its boolean
+// result.
+func evaluateCELPolicy(predicate interface{}, policy string) (bool, error) {
+	predicateMap, err := toPlainMap(predicate)
+	if err != nil {
+		return false, err
+	}
+
+	env, err := cel.NewEnv(cel.Variable("predicate", cel.DynType))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create CEL environment")
+	}
+
+	ast, issues := env.Compile(policy)
+	if issues != nil && issues.Err() != nil {
+		return false, errors.Wrap(issues.Err(), "failed to compile CEL policy")
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to build CEL program")
+	}
+
+	out, _, err := program.Eval(map[string]interface{}{"predicate": predicateMap})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to evaluate CEL policy")
+	}
+
+	passed, ok := out.Value().(bool)
+	if !ok {
+		return false, errors.New("CEL policy must evaluate to a boolean")
+	}
+	return passed, nil
+}
+
+// evaluateRegoPolicy evaluates policy as a Rego module against predicate (exposed as
+// `input`) and expects a `data.policy.allow` rule to exist and return a boolean.
+func evaluateRegoPolicy(predicate interface{}, policy string) (bool, error) {
+	predicateMap, err := toPlainMap(predicate)
+	if err != nil {
+		return false, err
+	}
+
+	query, err := rego.New(
+		rego.Query("data.policy.allow"),
+		rego.Module("policy.rego", policy),
+	).PrepareForEval(context.Background())
+	if err != nil {
+		return false, errors.Wrap(err, "failed to prepare Rego policy")
+	}
+
+	results, err := query.Eval(context.Background(), rego.EvalInput(predicateMap))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to evaluate Rego policy")
+	}
+	if len(results) == 0 || len(results[0].Expressions) == 0 {
+		return false, nil
+	}
+
+	passed, ok := results[0].Expressions[0].Value.(bool)
+	if !ok {
+		return false, errors.New("Rego policy `data.policy.allow` must evaluate to a boolean")
+	}
+	return passed, nil
+}
+
+// toPlainMap round-trips v through JSON so CEL/Rego can operate on plain
+// map[string]interface{} data regardless of v's concrete Go type.
+func toPlainMap(v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal predicate")
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal predicate")
+	}
+	return m, nil
+}
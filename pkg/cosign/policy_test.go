@@ -0,0 +1,71 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cosign
+
+import "testing"
+
+func TestEvaluateCELPolicy(t *testing.T) {
+	predicate := map[string]interface{}{"builder": "trusted-builder"}
+
+	passed, err := evaluateCELPolicy(predicate, `predicate.builder == "trusted-builder"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !passed {
+		t.Fatal("expected policy to pass")
+	}
+
+	passed, err = evaluateCELPolicy(predicate, `predicate.builder == "other-builder"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if passed {
+		t.Fatal("expected policy to fail")
+	}
+}
+
+func TestEvaluateCELPolicyNonBooleanResult(t *testing.T) {
+	_, err := evaluateCELPolicy(map[string]interface{}{}, `"not-a-bool"`)
+	if err == nil {
+		t.Fatal("expected an error for a non-boolean CEL result")
+	}
+}
+
+func TestEvaluateRegoPolicy(t *testing.T) {
+	predicate := map[string]interface{}{"builder": "trusted-builder"}
+	policy := `package policy
+
+allow {
+	input.builder == "trusted-builder"
+}`
+
+	passed, err := evaluateRegoPolicy(predicate, policy)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !passed {
+		t.Fatal("expected policy to pass")
+	}
+
+	passed, err = evaluateRegoPolicy(map[string]interface{}{"builder": "other-builder"}, policy)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if passed {
+		t.Fatal("expected policy to fail")
+	}
+}
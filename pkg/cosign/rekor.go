@@ -0,0 +1,45 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cosign
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sigstore/rekor/pkg/client"
+	"github.com/sigstore/rekor/pkg/generated/client/entries"
+)
+
+// fetchRekorEntryUUID resolves the UUID of the Rekor log entry at logIndex. The
+// verified oci.Signature/cosignoci.Bundle only carries the numeric log index and
+// integrated time; the UUID itself has to come from Rekor, the same way the
+// now-superseded getSignedTimestamp() sketch in verify.go used to look an entry up.
+func fetchRekorEntryUUID(rekorServerURL string, logIndex int64) (string, error) {
+	rekorClient, err := client.GetRekorClient(rekorServerURL)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create Rekor client")
+	}
+
+	params := entries.NewGetLogEntryByIndexParams()
+	params.SetLogIndex(logIndex)
+	resp, err := rekorClient.Entries.GetLogEntryByIndex(params)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get Rekor log entry by index")
+	}
+	for uuid := range resp.Payload {
+		return uuid, nil
+	}
+	return "", errors.New("empty response from Rekor")
+}
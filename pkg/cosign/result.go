@@ -0,0 +1,105 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cosign
+
+// VerificationMechanism identifies which trust path a signature was verified through.
+type VerificationMechanism string
+
+const (
+	// VerificationMechanismKey means a user-supplied public key verified the signature.
+	VerificationMechanismKey VerificationMechanism = "key"
+	// VerificationMechanismFulcioRekor means a Fulcio-issued cert verified the
+	// signature, with the Rekor transparency log establishing the signing time.
+	VerificationMechanismFulcioRekor VerificationMechanism = "fulcio-rekor"
+	// VerificationMechanismFulcioTSA means a Fulcio-issued cert verified the signature,
+	// with an RFC3161 timestamp establishing the signing time instead of Rekor.
+	VerificationMechanismFulcioTSA VerificationMechanism = "fulcio-tsa"
+	// VerificationMechanismBundle means verification used a self-contained Sigstore
+	// bundle (protobuf or the legacy message-signature/certificate/bundle triple),
+	// without talking to Rekor or Fulcio.
+	VerificationMechanismBundle VerificationMechanism = "bundle"
+)
+
+// SCTStatus describes the outcome of checking a certificate's embedded SCT.
+type SCTStatus string
+
+const (
+	// SCTStatusVerified means an embedded or detached SCT was found and verified
+	// against a known CT log public key.
+	SCTStatusVerified SCTStatus = "verified"
+	// SCTStatusSkipped means SCT verification was disabled via InsecureSkipSCTVerify.
+	SCTStatusSkipped SCTStatus = "skipped"
+	// SCTStatusInvalid means an embedded or detached SCT was found but failed to verify
+	// against every known CT log public key.
+	SCTStatusInvalid SCTStatus = "invalid"
+	// SCTStatusMissing means verification was not skipped, but the certificate had no
+	// embedded SCT and no detached SCT was provided.
+	SCTStatusMissing SCTStatus = "missing"
+	// SCTStatusNotApplicable means verification did not involve a Fulcio certificate
+	// (e.g. plain public-key verification), so no SCT was expected.
+	SCTStatusNotApplicable SCTStatus = "not-applicable"
+)
+
+// SignatureResult carries the per-signature diagnostics collected while verifying an
+// image or blob that may carry more than one signature. A signature that failed to
+// verify does not necessarily fail the overall VerificationResult, as long as at least
+// one signature in Signatures verified.
+type SignatureResult struct {
+	Verified   bool
+	SignerName string
+	Issuer     string
+	SCTStatus  SCTStatus
+	Error      error
+}
+
+// VerificationResult describes *how* an image or blob verified, not just whether it
+// did. This lets callers such as Kubernetes admission controllers implement policies a
+// plain boolean cannot express, e.g. requiring a specific Fulcio identity and issuer
+// together with a Rekor entry older than some age.
+type VerificationResult struct {
+	Verified  bool
+	Mechanism VerificationMechanism
+
+	// SignerName and Issuer describe the signer of the signature VerificationResult
+	// settled on (the first one in Signatures that verified).
+	SignerName string
+	Issuer     string
+
+	SCTStatus SCTStatus
+
+	RekorEntryUUID      string
+	RekorIntegratedTime *int64
+
+	TSASignedTime *int64
+
+	// Signatures holds per-signature diagnostics for every signature VerifyImage
+	// checked on the image; it is left empty for blob and bundle verification.
+	Signatures []SignatureResult
+}
+
+// SignedTimestamp returns the verified signing time, preferring the Rekor integrated
+// time when available and falling back to the RFC3161 TSA time. It returns nil when
+// neither is available, e.g. for plain public-key verification.
+func (r *VerificationResult) SignedTimestamp() *int64 {
+	if r == nil {
+		return nil
+	}
+	if r.RekorIntegratedTime != nil {
+		return r.RekorIntegratedTime
+	}
+	return r.TSASignedTime
+}
@@ -0,0 +1,63 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cosign
+
+import "testing"
+
+func TestVerificationResultSignedTimestamp(t *testing.T) {
+	rekorTime := int64(1000)
+	tsaTime := int64(2000)
+
+	cases := []struct {
+		name   string
+		result *VerificationResult
+		want   *int64
+	}{
+		{
+			name:   "nil receiver",
+			result: nil,
+			want:   nil,
+		},
+		{
+			name:   "neither set",
+			result: &VerificationResult{},
+			want:   nil,
+		},
+		{
+			name:   "prefers Rekor integrated time over TSA time",
+			result: &VerificationResult{RekorIntegratedTime: &rekorTime, TSASignedTime: &tsaTime},
+			want:   &rekorTime,
+		},
+		{
+			name:   "falls back to TSA time",
+			result: &VerificationResult{TSASignedTime: &tsaTime},
+			want:   &tsaTime,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := c.result.SignedTimestamp()
+			if (got == nil) != (c.want == nil) {
+				t.Fatalf("SignedTimestamp() = %v, want %v", got, c.want)
+			}
+			if got != nil && *got != *c.want {
+				t.Fatalf("SignedTimestamp() = %d, want %d", *got, *c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cosign
+
+import (
+	"crypto/x509"
+
+	"github.com/google/certificate-transparency-go/ctutil"
+	ctx509 "github.com/google/certificate-transparency-go/x509"
+	"github.com/google/certificate-transparency-go/x509util"
+	"github.com/pkg/errors"
+)
+
+// errSCTMissing is returned by verifySCT when the certificate has no embedded SCT and no
+// detached SCT was provided.
+var errSCTMissing = errors.New("certificate has no embedded SCT and no detached SCT was provided")
+
+// verifySCT verifies the Signed Certificate Timestamp for a Fulcio-issued leaf
+// certificate against every CT log public key known to the trust root provider. It
+// prefers the SCT(s) embedded in the certificate itself, falling back to detachedSCT -
+// a raw TLS-encoded SCT carried alongside the signature rather than in the cert, for
+// Fulcio deployments that hand out certs before the SCT is available - when the
+// certificate carries none. A missing or invalid SCT means the certificate never
+// appeared in a CT log, which a compromised Fulcio could otherwise exploit to mint
+// certs no one observes.
+//
+// issuer is the CA certificate that signed cert. An embedded SCT is signed over a
+// precertificate leaf that includes the issuer key hash, so it can't be verified from
+// the leaf alone; verifySCT requires issuer for detached SCTs as well.
+func verifySCT(cert, issuer *x509.Certificate, detachedSCT []byte) error {
+	ctCert, err := ctx509.ParseCertificate(cert.Raw)
+	if err != nil {
+		return errors.Wrap(err, "failed to reparse certificate for SCT extraction")
+	}
+
+	scts, err := x509util.ExtractSCTList(ctCert)
+	if err != nil {
+		return errors.Wrap(err, "failed to extract embedded SCT list")
+	}
+	embedded := len(scts) > 0
+	if !embedded {
+		if len(detachedSCT) == 0 {
+			return errSCTMissing
+		}
+		scts = [][]byte{detachedSCT}
+	}
+
+	if issuer == nil {
+		return errors.New("issuer certificate is required to verify a Signed Certificate Timestamp")
+	}
+	ctIssuer, err := ctx509.ParseCertificate(issuer.Raw)
+	if err != nil {
+		return errors.Wrap(err, "failed to reparse issuer certificate for SCT verification")
+	}
+	certs := []*ctx509.Certificate{ctCert, ctIssuer}
+
+	logKeys, err := defaultTrustRootProvider.CTLogPublicKeys()
+	if err != nil {
+		return errors.Wrap(err, "failed to get CT log public keys from trust root provider")
+	}
+	if len(logKeys) == 0 {
+		return errors.New("no CT log public keys available in trust root")
+	}
+
+	var lastErr error
+	for _, sct := range scts {
+		for _, pubKey := range logKeys {
+			if err := ctutil.VerifySCT(pubKey, certs, sct, embedded); err == nil {
+				return nil
+			} else {
+				lastErr = err
+			}
+		}
+	}
+	return errors.Wrap(lastErr, "SCT verification failed against all known CT logs")
+}
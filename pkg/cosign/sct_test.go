@@ -0,0 +1,56 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cosign
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+)
+
+func loadTestCert(t *testing.T, pemContent string) *x509.Certificate {
+	t.Helper()
+	block, _ := pem.Decode([]byte(pemContent))
+	if block == nil {
+		t.Fatal("failed to decode test certificate PEM")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("failed to parse test certificate: %s", err)
+	}
+	return cert
+}
+
+func TestVerifySCTRequiresAnSCT(t *testing.T) {
+	cert := loadTestCert(t, newSelfSignedTestCert(t))
+	issuer := loadTestCert(t, newSelfSignedTestCert(t))
+
+	err := verifySCT(cert, issuer, nil)
+	if err == nil || !strings.Contains(err.Error(), "no embedded SCT and no detached SCT was provided") {
+		t.Fatalf("expected a missing-SCT error, got %v", err)
+	}
+}
+
+func TestVerifySCTRequiresAnIssuer(t *testing.T) {
+	cert := loadTestCert(t, newSelfSignedTestCert(t))
+
+	err := verifySCT(cert, nil, []byte("detached-sct-bytes"))
+	if err == nil || !strings.Contains(err.Error(), "issuer certificate is required") {
+		t.Fatalf("expected an issuer-required error, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cosign
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	cliopt "github.com/sigstore/cosign/cmd/cosign/cli/options"
+	clisign "github.com/sigstore/cosign/cmd/cosign/cli/sign"
+	fulcioclient "github.com/sigstore/fulcio/pkg/client"
+)
+
+// SignManifest signs msgBytes the same way `cosign sign-blob` would, and additionally
+// requests an RFC3161 timestamp token from tsaServerURL (when non-empty) covering the
+// produced signature so that the manifest can later be verified offline without relying
+// on Rekor. keyPath may be empty to use keyless (Fulcio) signing.
+func SignManifest(msgBytes []byte, keyPath, tsaServerURL string) (sigBytes, certBytes, tsaBytes []byte, err error) {
+	dir, err := ioutil.TempDir("", "kubectl-sigstore-temp-dir")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	msgFile := filepath.Join(dir, tmpMessageFile)
+	if err := ioutil.WriteFile(msgFile, msgBytes, 0777); err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to write message to a temp file")
+	}
+	sigFile := filepath.Join(dir, tmpSignatureFile)
+	certFile := filepath.Join(dir, tmpCertificateFile)
+
+	opt := clisign.KeyOpts{
+		KeyRef:       keyPath,
+		RekorURL:     GetRekorServerURL(),
+		FulcioURL:    fulcioclient.SigstorePublicServerURL,
+		OIDCIssuer:   defaultOIDCIssuer,
+		OIDCClientID: defaultOIDCClientID,
+		TSAServerURL: tsaServerURL,
+	}
+
+	ro := &cliopt.RootOptions{Timeout: cliopt.DefaultTimeout}
+	sigBytes, err = clisign.SignBlobCmd(ro, opt, msgFile, true, sigFile, certFile, true)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "cosign.SignBlobCmd() returned an error")
+	}
+
+	if keyPath == "" {
+		certBytes, err = ioutil.ReadFile(certFile)
+		if err != nil {
+			return nil, nil, nil, errors.Wrap(err, "failed to read generated certificate")
+		}
+	}
+
+	if tsaServerURL != "" {
+		tsaBytes, err = requestTimestamp(context.Background(), tsaServerURL, sigBytes)
+		if err != nil {
+			return nil, nil, nil, errors.Wrap(err, "failed to obtain RFC3161 timestamp")
+		}
+	}
+
+	return sigBytes, certBytes, tsaBytes, nil
+}
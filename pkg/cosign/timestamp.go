@@ -0,0 +1,118 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cosign
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	tsaclient "github.com/sigstore/timestamp-authority/pkg/client"
+	tsaverification "github.com/sigstore/timestamp-authority/pkg/verification"
+)
+
+const defaultTSAServerURL = "https://timestamp.sigstore.dev/api/v1/timestamp"
+
+// GetTSAServerURL returns the RFC3161 Timestamp Authority endpoint used to request and
+// verify timestamp tokens. It can be overridden with the TSA_SERVER_URL env var, e.g.
+// to point at a private TSA for air-gapped clusters.
+func GetTSAServerURL() string {
+	if url := os.Getenv("TSA_SERVER_URL"); url != "" {
+		return url
+	}
+	return defaultTSAServerURL
+}
+
+// verifyTimestamp verifies an RFC3161 timestamp token against the TSA certificate chain
+// found at tsaCertPoolPath and checks that it covers signedArtifact (the exact bytes the
+// TSA was asked to timestamp - the signature, not the raw manifest). The PEM file at
+// tsaCertPoolPath must list the TSA leaf certificate first, any intermediates next, and
+// the root CA last; a single-certificate file is treated as a self-signed TSA root. On
+// success it returns the signed time as unix seconds.
+func verifyTimestamp(tsaRespBytes, signedArtifact []byte, tsaCertPoolPath string) (*int64, error) {
+	if len(tsaRespBytes) == 0 {
+		return nil, errors.New("empty timestamp token")
+	}
+	if tsaCertPoolPath == "" {
+		return nil, errors.New("tsaCertPoolPath must be set to verify a timestamp token")
+	}
+
+	certPoolPEM, err := ioutil.ReadFile(tsaCertPoolPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read TSA cert pool")
+	}
+	certs, err := parseCertsPEM(certPoolPEM)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse TSA cert pool")
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificates found in TSA cert pool")
+	}
+
+	leaf := certs[0]
+	root := certs[len(certs)-1]
+	var intermediates []*x509.Certificate
+	if len(certs) > 2 {
+		intermediates = certs[1 : len(certs)-1]
+	}
+
+	opts := tsaverification.VerifyOpts{
+		Roots:          []*x509.Certificate{root},
+		Intermediates:  intermediates,
+		TSACertificate: leaf,
+	}
+
+	ts, err := tsaverification.VerifyTimestampResponse(tsaRespBytes, bytes.NewReader(signedArtifact), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify timestamp response")
+	}
+
+	signedTime := ts.Time.Unix()
+	return &signedTime, nil
+}
+
+// requestTimestamp asks the TSA at tsaServerURL to timestamp sigBytes and returns the
+// raw RFC3161 timestamp token.
+func requestTimestamp(ctx context.Context, tsaServerURL string, sigBytes []byte) ([]byte, error) {
+	client, err := tsaclient.GetTimestampClient(tsaServerURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create TSA client")
+	}
+	return tsaclient.GetTimestampResponse(ctx, client, bytes.NewReader(sigBytes))
+}
+
+func parseCertsPEM(pemBytes []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := pemBytes
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
@@ -0,0 +1,80 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cosign
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyTimestampRejectsInvalidInput(t *testing.T) {
+	certPool := filepath.Join(t.TempDir(), "tsa-certs.pem")
+	writeTestPEM(t, certPool, newSelfSignedTestCert(t))
+
+	cases := []struct {
+		name            string
+		tsaRespBytes    []byte
+		signedArtifact  []byte
+		tsaCertPoolPath string
+		wantErr         string
+	}{
+		{
+			name:            "empty timestamp token",
+			tsaRespBytes:    nil,
+			signedArtifact:  []byte("signature-bytes"),
+			tsaCertPoolPath: certPool,
+			wantErr:         "empty timestamp token",
+		},
+		{
+			name:            "missing cert pool path",
+			tsaRespBytes:    []byte("not-a-real-tsr"),
+			signedArtifact:  []byte("signature-bytes"),
+			tsaCertPoolPath: "",
+			wantErr:         "tsaCertPoolPath must be set",
+		},
+		{
+			name:            "cert pool file does not exist",
+			tsaRespBytes:    []byte("not-a-real-tsr"),
+			signedArtifact:  []byte("signature-bytes"),
+			tsaCertPoolPath: filepath.Join(t.TempDir(), "does-not-exist.pem"),
+			wantErr:         "failed to read TSA cert pool",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := verifyTimestamp(c.tsaRespBytes, c.signedArtifact, c.tsaCertPoolPath)
+			if err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+			if !strings.Contains(err.Error(), c.wantErr) {
+				t.Fatalf("error %q does not contain %q", err.Error(), c.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyTimestampRejectsEmptyCertPool(t *testing.T) {
+	certPool := filepath.Join(t.TempDir(), "tsa-certs.pem")
+	writeTestPEM(t, certPool, "")
+
+	_, err := verifyTimestamp([]byte("not-a-real-tsr"), []byte("signature-bytes"), certPool)
+	if err == nil || !strings.Contains(err.Error(), "no certificates found in TSA cert pool") {
+		t.Fatalf("expected a no-certificates error, got %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cosign
+
+import (
+	"crypto"
+	"crypto/x509"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/sigstore/sigstore-go/pkg/root"
+	"github.com/sigstore/sigstore-go/pkg/tuf"
+)
+
+// defaultTrustRootTTL controls how long a fetched trusted_root.json is reused before
+// TrustRootProvider refreshes it from the TUF mirror.
+const defaultTrustRootTTL = 1 * time.Hour
+
+// TrustRootProvider fetches and caches the Sigstore `trusted_root.json` (Fulcio CAs,
+// Rekor/CTLog public keys, TSA certificates) from a TUF repository, so key rotations in
+// the public-good Sigstore instance (or in a private deployment) propagate to verifiers
+// without a code release.
+type TrustRootProvider struct {
+	tufMirror string
+	rootFile  string
+	ttl       time.Duration
+
+	mu        sync.Mutex
+	trusted   *root.TrustedRoot
+	fetchedAt time.Time
+}
+
+// NewTrustRootProvider creates a TrustRootProvider. The TUF mirror and initial root file
+// default to the public-good Sigstore instance, but can be overridden with the
+// SIGSTORE_TUF_MIRROR and SIGSTORE_ROOT_FILE env vars to point at a private deployment.
+func NewTrustRootProvider() *TrustRootProvider {
+	return &TrustRootProvider{
+		tufMirror: os.Getenv("SIGSTORE_TUF_MIRROR"),
+		rootFile:  os.Getenv("SIGSTORE_ROOT_FILE"),
+		ttl:       defaultTrustRootTTL,
+	}
+}
+
+// Get returns the cached trusted root, refreshing it from the TUF mirror if the TTL has
+// expired or nothing has been fetched yet.
+func (p *TrustRootProvider) Get() (*root.TrustedRoot, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.trusted != nil && time.Since(p.fetchedAt) < p.ttl {
+		return p.trusted, nil
+	}
+
+	opts := tuf.DefaultOptions()
+	if p.tufMirror != "" {
+		opts.RepositoryBaseURL = p.tufMirror
+	}
+	if p.rootFile != "" {
+		rootJSON, err := os.ReadFile(p.rootFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read SIGSTORE_ROOT_FILE")
+		}
+		opts.Root = rootJSON
+	}
+
+	tufClient, err := tuf.New(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create TUF client")
+	}
+
+	trustedRoot, err := root.NewTrustedRootFromTUF(tufClient)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch trusted_root.json from TUF")
+	}
+
+	p.trusted = trustedRoot
+	p.fetchedAt = time.Now()
+	return p.trusted, nil
+}
+
+// FulcioCertPool returns the current set of Fulcio CA certificates as an x509.CertPool,
+// suitable for use as cosign.CheckOpts.RootCerts.
+func (p *TrustRootProvider) FulcioCertPool() (*x509.CertPool, error) {
+	trustedRoot, err := p.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	for _, ca := range trustedRoot.FulcioCertAuthorities() {
+		for _, cert := range ca.Certificates {
+			pool.AddCert(cert)
+		}
+	}
+	return pool, nil
+}
+
+// CTLogPublicKeys returns the public keys of every known Certificate Transparency log,
+// used to verify the Signed Certificate Timestamp embedded in Fulcio-issued certificates.
+func (p *TrustRootProvider) CTLogPublicKeys() ([]crypto.PublicKey, error) {
+	trustedRoot, err := p.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []crypto.PublicKey
+	for _, ctLog := range trustedRoot.CTLogs() {
+		keys = append(keys, ctLog.PublicKey)
+	}
+	return keys, nil
+}
+
+// RekorPublicKeys returns the public keys of every known Rekor transparency log,
+// used to verify inclusion proofs/SETs without trusting a hardcoded key.
+func (p *TrustRootProvider) RekorPublicKeys() ([]crypto.PublicKey, error) {
+	trustedRoot, err := p.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []crypto.PublicKey
+	for _, rekorLog := range trustedRoot.RekorLogs() {
+		keys = append(keys, rekorLog.PublicKey)
+	}
+	return keys, nil
+}
+
+// RekorURL returns the base URL of the Rekor transparency log named in the trusted
+// root, falling back to GetRekorServerURL() (e.g. the REKOR_SERVER env var) when the
+// trusted root carries keys but no usable base URL, which is normal for a private
+// deployment that reuses the public-good log's URL conventions.
+func (p *TrustRootProvider) RekorURL() (string, error) {
+	trustedRoot, err := p.Get()
+	if err != nil {
+		return "", err
+	}
+
+	for _, rekorLog := range trustedRoot.RekorLogs() {
+		if rekorLog.BaseURL != "" {
+			return rekorLog.BaseURL, nil
+		}
+	}
+	return GetRekorServerURL(), nil
+}
+
+// TSACertificates returns the certificate chains of every known Timestamp Authority,
+// for verifying RFC3161 tokens without a user-supplied --tsa-cert-pool file.
+func (p *TrustRootProvider) TSACertificates() ([]*x509.Certificate, error) {
+	trustedRoot, err := p.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []*x509.Certificate
+	for _, ca := range trustedRoot.TSACertAuthorities() {
+		certs = append(certs, ca.Certificates...)
+	}
+	return certs, nil
+}
+
+// defaultTrustRootProvider is the process-wide provider used by verification callers
+// that don't need a dedicated instance (e.g. tests pointed at a private deployment).
+var defaultTrustRootProvider = NewTrustRootProvider()
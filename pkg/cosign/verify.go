@@ -26,13 +26,13 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
-	"github.com/sigstore/cosign/cmd/cosign/cli/fulcio"
 	cliopt "github.com/sigstore/cosign/cmd/cosign/cli/options"
 	clisign "github.com/sigstore/cosign/cmd/cosign/cli/sign"
 	cliverify "github.com/sigstore/cosign/cmd/cosign/cli/verify"
@@ -50,20 +50,37 @@ const (
 	tmpMessageFile     = "k8s-manifest-sigstore-message"
 	tmpCertificateFile = "k8s-manifest-sigstore-certificate"
 	tmpSignatureFile   = "k8s-manifest-sigstore-signature"
+	tmpTSAFile         = "k8s-manifest-sigstore-tsa"
 )
 
-func VerifyImage(imageRef string, pubkeyPath string) (bool, string, *int64, error) {
+// sctAnnotationKey is the OCI signature annotation carrying a base64-encoded, raw
+// TLS-encoded detached SCT, for Fulcio certs issued without one embedded.
+const sctAnnotationKey = "dev.sigstore.cosign/sct"
+
+// VerifyImageOptions customizes VerifyImage. The zero value performs full verification,
+// including SCT verification.
+type VerifyImageOptions struct {
+	// InsecureSkipSCTVerify skips validating the Signed Certificate Timestamp embedded
+	// in the Fulcio-issued leaf certificate. Only set this against private Fulcio
+	// deployments that do not publish to a CT log.
+	InsecureSkipSCTVerify bool
+}
+
+func VerifyImage(imageRef string, pubkeyPath string, vo VerifyImageOptions) (*VerificationResult, error) {
 	ref, err := name.ParseReference(imageRef)
 	if err != nil {
-		return false, "", nil, fmt.Errorf("failed to parse image ref `%s`; %s", imageRef, err.Error())
+		return nil, fmt.Errorf("failed to parse image ref `%s`; %s", imageRef, err.Error())
 	}
 
-	rekorSeverURL := GetRekorServerURL()
+	rekorSeverURL, err := defaultTrustRootProvider.RekorURL()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get Rekor URL from trust root provider")
+	}
 
 	regOpt := &cliopt.RegistryOptions{}
 	reqCliOpt, err := regOpt.ClientOpts(context.Background())
 	if err != nil {
-		return false, "", nil, fmt.Errorf("failed to get registry client option; %s", err.Error())
+		return nil, fmt.Errorf("failed to get registry client option; %s", err.Error())
 	}
 
 	co := &cosign.CheckOpts{
@@ -71,13 +88,19 @@ func VerifyImage(imageRef string, pubkeyPath string) (bool, string, *int64, erro
 		RegistryClientOpts: reqCliOpt,
 	}
 
+	mechanism := VerificationMechanismFulcioRekor
 	if pubkeyPath == "" {
 		co.RekorURL = rekorSeverURL
-		co.RootCerts = fulcio.GetRoots()
+		rootCerts, err := defaultTrustRootProvider.FulcioCertPool()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get Fulcio roots from trust root provider")
+		}
+		co.RootCerts = rootCerts
 	} else {
+		mechanism = VerificationMechanismKey
 		pubKeyVerifier, err := sigs.PublicKeyFromKeyRef(context.Background(), pubkeyPath)
 		if err != nil {
-			return false, "", nil, fmt.Errorf("failed to load public key; %s", err.Error())
+			return nil, fmt.Errorf("failed to load public key; %s", err.Error())
 		}
 		pkcs11Key, ok := pubKeyVerifier.(*pkcs11key.Key)
 		if ok {
@@ -88,43 +111,123 @@ func VerifyImage(imageRef string, pubkeyPath string) (bool, string, *int64, erro
 
 	checkedSigs, _, err := cosign.VerifyImageSignatures(context.Background(), ref, co)
 	if err != nil {
-		return false, "", nil, fmt.Errorf("error occured while verifying image `%s`; %s", imageRef, err.Error())
+		return nil, fmt.Errorf("error occured while verifying image `%s`; %s", imageRef, err.Error())
 	}
 	if len(checkedSigs) == 0 {
-		return false, "", nil, fmt.Errorf("no verified signatures in the image `%s`; %s", imageRef, err.Error())
+		return nil, fmt.Errorf("no verified signatures in the image `%s`; %s", imageRef, err.Error())
 	}
-	var cert *x509.Certificate
-	var signedTimestamp *int64
+
+	result := &VerificationResult{Mechanism: mechanism}
 	for _, s := range checkedSigs {
+		sigResult := SignatureResult{Verified: true}
+
 		payloadBytes, err := s.Payload()
 		if err != nil {
+			sigResult.Verified = false
+			sigResult.Error = errors.Wrap(err, "failed to get payload")
+			result.Signatures = append(result.Signatures, sigResult)
 			continue
 		}
 		ss := payload.SimpleContainerImage{}
-		err = json.Unmarshal(payloadBytes, &ss)
-		if err != nil {
+		if err := json.Unmarshal(payloadBytes, &ss); err != nil {
+			sigResult.Verified = false
+			sigResult.Error = errors.Wrap(err, "failed to unmarshal payload")
+			result.Signatures = append(result.Signatures, sigResult)
 			continue
 		}
-		// if tstamp, err := getSignedTimestamp(rekorSever, vp, co); err == nil {
-		// 	signedTimestamp = tstamp
-		// }
-		cert, err = s.Cert()
+
+		cert, err := s.Cert()
 		if err != nil {
+			// key-only verification has no cert, which is not an error
+			sigResult.SCTStatus = SCTStatusNotApplicable
+			result.Signatures = append(result.Signatures, sigResult)
+			if result.SignerName == "" {
+				result.SignerName = sigResult.SignerName
+				result.SCTStatus = sigResult.SCTStatus
+			}
 			continue
 		}
-		break
+
+		var detachedSCT []byte
+		if annotations, err := s.Annotations(); err == nil {
+			if raw, ok := annotations[sctAnnotationKey]; ok {
+				if decoded, err := base64.StdEncoding.DecodeString(raw); err == nil {
+					detachedSCT = decoded
+				} else {
+					log.Debugf("failed to decode %s annotation: %s", sctAnnotationKey, err.Error())
+				}
+			}
+		}
+
+		var issuer *x509.Certificate
+		if chain, err := s.Chain(); err == nil && len(chain) > 1 {
+			issuer = chain[1]
+		}
+
+		sigResult.SignerName = k8smnfutil.GetNameInfoFromCert(cert)
+		sigResult.Issuer = cosign.CertExtensions(cert).GetIssuer()
+		if vo.InsecureSkipSCTVerify {
+			sigResult.SCTStatus = SCTStatusSkipped
+		} else if err := verifySCT(cert, issuer, detachedSCT); err != nil {
+			sigResult.Verified = false
+			sigResult.SCTStatus = SCTStatusInvalid
+			if err == errSCTMissing {
+				sigResult.SCTStatus = SCTStatusMissing
+			}
+			sigResult.Error = errors.Wrap(err, "failed to verify SCT")
+		} else {
+			sigResult.SCTStatus = SCTStatusVerified
+		}
+
+		var rekorIntegratedTime *int64
+		var rekorEntryUUID string
+		if b, err := s.Bundle(); err == nil && b != nil {
+			it := b.Payload.IntegratedTime
+			rekorIntegratedTime = &it
+			if uuid, err := fetchRekorEntryUUID(rekorSeverURL, b.Payload.LogIndex); err == nil {
+				rekorEntryUUID = uuid
+			} else {
+				log.Debugf("failed to resolve Rekor entry UUID for log index %d: %s", b.Payload.LogIndex, err)
+			}
+		}
+
+		result.Signatures = append(result.Signatures, sigResult)
+
+		if sigResult.Verified && result.SignerName == "" {
+			result.SignerName = sigResult.SignerName
+			result.Issuer = sigResult.Issuer
+			result.SCTStatus = sigResult.SCTStatus
+			result.RekorIntegratedTime = rekorIntegratedTime
+			result.RekorEntryUUID = rekorEntryUUID
+		}
 	}
-	signerName := "" // singerName could be empty in case of key-used verification
-	if cert != nil {
-		signerName = k8smnfutil.GetNameInfoFromCert(cert)
+
+	for _, sigResult := range result.Signatures {
+		if sigResult.Verified {
+			result.Verified = true
+			break
+		}
 	}
-	return true, signerName, signedTimestamp, nil
+	if !result.Verified {
+		return nil, errors.New("no signature passed SCT verification")
+	}
+
+	return result, nil
 }
 
-func VerifyBlob(msgBytes, sigBytes, certBytes, bundleBytes []byte, pubkeyPath *string) (bool, string, *int64, error) {
+// VerifyBlobOptions customizes VerifyBlob. The zero value performs full verification,
+// including SCT verification.
+type VerifyBlobOptions struct {
+	// InsecureSkipSCTVerify skips validating the Signed Certificate Timestamp embedded
+	// in the Fulcio-issued leaf certificate. Only set this against private Fulcio
+	// deployments that do not publish to a CT log.
+	InsecureSkipSCTVerify bool
+}
+
+func VerifyBlob(msgBytes, sigBytes, certBytes, issuerCertBytes, bundleBytes, tsaBytes, sctBytes []byte, tsaCertPoolPath string, pubkeyPath *string, vo VerifyBlobOptions) (*VerificationResult, error) {
 	dir, err := ioutil.TempDir("", "kubectl-sigstore-temp-dir")
 	if err != nil {
-		return false, "", nil, err
+		return nil, err
 	}
 	defer os.RemoveAll(dir)
 
@@ -150,11 +253,11 @@ func VerifyBlob(msgBytes, sigBytes, certBytes, bundleBytes []byte, pubkeyPath *s
 	if bundleBytes != nil {
 		gzipBundle, _ := base64.StdEncoding.DecodeString(string(bundleBytes))
 		rawBundle := k8smnfutil.GzipDecompress(gzipBundle)
-		verified, signerName, signedTimestamp, err := verifyBundle(sigBytes, rawCert, rawBundle)
-		log.Debugf("verifyBundle() results: verified: %v, signerName: %s, err: %s", verified, signerName, err)
-		if verified {
+		result, err := verifyBundle(sigBytes, rawCert, rawBundle)
+		log.Debugf("verifyBundle() results: result: %+v, err: %s", result, err)
+		if result != nil && result.Verified {
 			log.Debug("Verified by bundle information")
-			return verified, signerName, signedTimestamp, err
+			return result, err
 		}
 	}
 	// otherwise, use cosign.VerifyBundleCmd for verification
@@ -163,7 +266,10 @@ func VerifyBlob(msgBytes, sigBytes, certBytes, bundleBytes []byte, pubkeyPath *s
 	sk := false
 	idToken := ""
 
-	rekorSeverURL := GetRekorServerURL()
+	rekorSeverURL, err := defaultTrustRootProvider.RekorURL()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get Rekor URL from trust root provider")
+	}
 	fulcioServerURL := fulcioclient.SigstorePublicServerURL
 
 	opt := clisign.KeyOpts{
@@ -181,23 +287,65 @@ func VerifyBlob(msgBytes, sigBytes, certBytes, bundleBytes []byte, pubkeyPath *s
 
 	err = cliverify.VerifyBlobCmd(context.Background(), opt, certFile, sigFile, msgFile)
 	if err != nil {
-		return false, "", nil, errors.Wrap(err, "cosign.VerifyBlobCmd() returned an error")
+		return nil, errors.Wrap(err, "cosign.VerifyBlobCmd() returned an error")
 	}
-	verified := false
-	if err == nil {
-		verified = true
+
+	result := &VerificationResult{Verified: true, Mechanism: VerificationMechanismFulcioRekor, SCTStatus: SCTStatusNotApplicable}
+	if pubkeyPath != nil {
+		result.Mechanism = VerificationMechanismKey
 	}
 
-	var signerName string
 	if rawCert != nil {
 		cert, err := loadCertificate(rawCert)
 		if err != nil {
-			return false, "", nil, errors.Wrap(err, "failed to load certificate")
+			return nil, errors.Wrap(err, "failed to load certificate")
+		}
+		var rawSCT []byte
+		if sctBytes != nil {
+			gzipSCT, _ := base64.StdEncoding.DecodeString(string(sctBytes))
+			rawSCT = k8smnfutil.GzipDecompress(gzipSCT)
+		}
+
+		var issuer *x509.Certificate
+		if issuerCertBytes != nil {
+			gzipIssuer, _ := base64.StdEncoding.DecodeString(string(issuerCertBytes))
+			rawIssuer := k8smnfutil.GzipDecompress(gzipIssuer)
+			issuer, err = loadCertificate(rawIssuer)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to load issuer certificate")
+			}
+		}
+
+		result.SignerName = k8ssigx509.GetNameInfoFromX509Cert(cert)
+		result.Issuer = cosign.CertExtensions(cert).GetIssuer()
+		if vo.InsecureSkipSCTVerify {
+			result.SCTStatus = SCTStatusSkipped
+		} else if err := verifySCT(cert, issuer, rawSCT); err != nil {
+			return nil, errors.Wrap(err, "failed to verify SCT")
+		} else {
+			result.SCTStatus = SCTStatusVerified
+		}
+		// RekorEntryUUID/RekorIntegratedTime are left unset here: cliverify.VerifyBlobCmd
+		// verifies the Rekor entry internally but doesn't hand back the entry it found.
+		// VerifyImage populates them because cosign.VerifyImageSignatures gives us the
+		// oci.Signature (and its Bundle()) directly.
+	}
+
+	if tsaBytes != nil {
+		gzipTSA, _ := base64.StdEncoding.DecodeString(string(tsaBytes))
+		rawTSA := k8smnfutil.GzipDecompress(gzipTSA)
+		signedTime, err := verifyTimestamp(rawTSA, rawSig, tsaCertPoolPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to verify RFC3161 timestamp")
+		}
+		log.Debugf("verified RFC3161 timestamp, signed at %v", signedTime)
+		if rawCert != nil {
+			result.Mechanism = VerificationMechanismFulcioTSA
 		}
-		signerName = k8ssigx509.GetNameInfoFromX509Cert(cert)
+		result.TSASignedTime = signedTime
 	}
 
-	return verified, signerName, nil, nil
+	return result, nil
 }
 
 func loadCertificate(pemBytes []byte) (*x509.Certificate, error) {
@@ -247,22 +395,57 @@ func loadCertificate(pemBytes []byte) (*x509.Certificate, error) {
 // 	return nil, errors.New("empty response")
 // }
 
-func verifyBundle(b64Sig, rawCert, rawBundle []byte) (bool, string, *int64, error) {
+func verifyBundle(b64Sig, rawCert, rawBundle []byte) (*VerificationResult, error) {
 	sig := &cosignBundleSignature{
 		base64Signature: b64Sig,
 		cert:            rawCert,
 		bundle:          rawBundle,
 	}
+
+	if rawCert != nil {
+		cert, err := loadCertificate(rawCert)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load certificate embedded in bundle")
+		}
+		fulcioRoots, err := defaultTrustRootProvider.FulcioCertPool()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get Fulcio roots from trust root provider")
+		}
+
+		// The whole point of bundle verification is validating a signature offline,
+		// long after the short-lived Fulcio leaf has expired, so the chain must be
+		// checked as of the signing time (the bundle's Rekor-integrated time) rather
+		// than time.Now(). Fall back to the leaf's own NotBefore if the bundle doesn't
+		// carry one yet.
+		currentTime := cert.NotBefore
+		if b, err := sig.Bundle(); err == nil && b != nil {
+			currentTime = time.Unix(b.Payload.IntegratedTime, 0)
+		}
+
+		if _, err := cert.Verify(x509.VerifyOptions{Roots: fulcioRoots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}, CurrentTime: currentTime}); err != nil {
+			return nil, errors.Wrap(err, "certificate embedded in bundle does not chain to a trusted Fulcio root")
+		}
+	}
+
 	verified, err := cosign.VerifyBundle(sig)
 	if err != nil {
-		return false, "", nil, errors.Wrap(err, "verifying bundle")
+		return nil, errors.Wrap(err, "verifying bundle")
 	}
-	var signerName string
+	result := &VerificationResult{Verified: verified, Mechanism: VerificationMechanismBundle}
 	if verified {
 		cert, _ := sig.Cert()
-		signerName = k8ssigx509.GetNameInfoFromX509Cert(cert)
+		result.SignerName = k8ssigx509.GetNameInfoFromX509Cert(cert)
+		result.Issuer = cosign.CertExtensions(cert).GetIssuer()
+
+		// The legacy bundle already carries the Rekor entry's integrated time offline.
+		// VerifyImage resolves the entry UUID from the log index with a Rekor lookup;
+		// this offline path makes no such call, so RekorEntryUUID is left unset here.
+		if b, err := sig.Bundle(); err == nil && b != nil {
+			it := b.Payload.IntegratedTime
+			result.RekorIntegratedTime = &it
+		}
 	}
-	return verified, signerName, nil, nil
+	return result, nil
 }
 
 type cosignBundleSignature struct {